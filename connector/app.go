@@ -5,16 +5,21 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
+	"text/template"
 	"time"
 
 	"github.com/fsnotify/fsnotify"
+	"github.com/gorilla/websocket"
 	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
@@ -28,11 +33,38 @@ type App struct {
 	ctx            context.Context
 	targetFileName string
 	SaveData       SaveData
+
+	watchMutex  sync.Mutex
+	watchCancel context.CancelFunc
+	watchWG     sync.WaitGroup
+
+	broadcastMutex   sync.Mutex
+	broadcastServer  *http.Server
+	broadcastClients map[*broadcastClient]bool
 }
 
 type SaveData struct {
-	LogPath  string    `json:"path"`
-	Settings []Setting `json:"settings"`
+	LogPath         string    `json:"path"`
+	Settings        []Setting `json:"settings"`
+	XSOverlayAPIKey string    `json:"xsOverlayApiKey"`
+	WatchRecursive  bool      `json:"watchRecursive"`
+	FilePattern     string    `json:"filePattern"`
+	BroadcastPort   int       `json:"broadcastPort"`
+}
+
+// maxWatchedDirs bounds how many subdirectories a recursive watch will add,
+// so a misconfigured root can't exhaust the OS's inotify handles.
+const maxWatchedDirs = 1000
+
+// defaultFilePattern is used when SaveData.FilePattern is unset.
+const defaultFilePattern = "output_log_*.txt"
+
+// filePattern returns the configured glob pattern, falling back to the default.
+func (a *App) filePattern() string {
+	if a.SaveData.FilePattern == "" {
+		return defaultFilePattern
+	}
+	return a.SaveData.FilePattern
 }
 
 type HttpRequestModel struct {
@@ -40,20 +72,81 @@ type HttpRequestModel struct {
 	Title string `json:"title"`
 }
 
+// BroadcastEvent is the frame sent to every subscribed WebSocket client
+// whenever a Setting's regexp matches a line.
+type BroadcastEvent struct {
+	SettingID string `json:"setting_id"`
+	Title     string `json:"title"`
+	Type      string `json:"type"`
+	Match     string `json:"match"`
+	Line      string `json:"line"`
+	Timestamp string `json:"timestamp"`
+}
+
+// broadcastClient is a subscribed WebSocket connection. send is buffered so a
+// slow consumer can't block log parsing; broadcastEvent drops the oldest
+// queued frame instead of blocking when the buffer is full. once ensures the
+// reader and writer goroutines don't both try to remove/close the client.
+type broadcastClient struct {
+	conn *websocket.Conn
+	send chan []byte
+	once sync.Once
+}
+
+// XSOverlayMessageModel is the envelope XSOverlay's local API expects:
+// https://github.com/XSOverlay/XSOverlay-Local-API
+type XSOverlayMessageModel struct {
+	Sender   string `json:"sender"`
+	Target   string `json:"target"`
+	Command  string `json:"command"`
+	JsonData string `json:"jsonData"`
+}
+
+// XSOverrayModel is the jsonData payload for a SendNotification command.
 type XSOverrayModel struct {
+	Type      int     `json:"type"`
+	Timeout   float64 `json:"timeout"`
+	Title     string  `json:"title"`
+	Content   string  `json:"content"`
+	Icon      string  `json:"icon"`
+	Opacity   float64 `json:"opacity"`
+	SourceApp string  `json:"sourceApp"`
 }
 
 type LogOutputModel struct {
 }
 
 type Setting struct {
-	ID      string `json:"id"`
-	Title   string `json:"title"`
-	Details string `json:"details"`
-	Target  string `json:"target"`
-	Type    string `json:"type"`
-	URL     string `json:"url"`
-	RegExp  string `json:"regexp"`
+	ID      string  `json:"id"`
+	Title   string  `json:"title"`
+	Details string  `json:"details"`
+	Target  string  `json:"target"`
+	Type    string  `json:"type"`
+	URL     string  `json:"url"`
+	RegExp  string  `json:"regexp"`
+	Timeout float64 `json:"timeout"`
+	Icon    string  `json:"icon"`
+	Opacity float64 `json:"opacity"`
+
+	// Web Request options. Method defaults to POST, ContentType to application/json.
+	// When BodyTemplate is set it is rendered with text/template instead of the
+	// default {"value":...,"title":...} body. Fields such as {{.Match}} or
+	// {{.Groups.user}} carry untrusted text (e.g. a VRChat display name) and
+	// must be piped through json (e.g. {{.Match | json}}) to come out as a valid, escaped
+	// JSON string literal.
+	Method       string            `json:"method"`
+	Headers      map[string]string `json:"headers"`
+	ContentType  string            `json:"contentType"`
+	BodyTemplate string            `json:"bodyTemplate"`
+}
+
+// HttpBodyTemplateData is the context exposed to a Setting's BodyTemplate.
+type HttpBodyTemplateData struct {
+	Match     string
+	Line      string
+	Title     string
+	Timestamp string
+	Groups    map[string]string
 }
 
 func NewApp() *App {
@@ -71,9 +164,12 @@ func (a *App) OutputLog(logstring string) {
 
 func (a *App) SetFileName(fileName string) {
 	log.Default().Println("[DEBUG] [LOG] SetFileName:" + fileName)
-	a.targetFileName = fileName
+	a.StopWatching()
+	a.setTargetFileName(fileName)
 	// setIntervalごとにファイルの内容も確認
-	a.ReadFile(a.SaveData.LogPath + "\\" + a.targetFileName)
+	if err := a.ReadFile(a.SaveData.LogPath + "\\" + a.getTargetFileName()); err != nil {
+		runtime.EventsEmit(a.ctx, "commonLogOutput", "ERRPR:"+err.Error())
+	}
 }
 
 func (a *App) LoadSetting() SaveData {
@@ -100,6 +196,14 @@ func (a *App) LoadSetting() SaveData {
 
 func (a *App) UpdateSetting(ss []Setting) {
 	log.Default().Println("[DEBUG] [LOG] UpdateSetting:", len(ss))
+	for _, s := range ss {
+		if s.BodyTemplate == "" {
+			continue
+		}
+		if _, err := template.New(s.ID).Funcs(bodyTemplateFuncs()).Parse(s.BodyTemplate); err != nil {
+			runtime.EventsEmit(a.ctx, "commonLogOutput", "ERRPR:"+s.Title+" bodyTemplate: "+err.Error())
+		}
+	}
 	a.SaveData.Settings = ss
 	// StructをJSONに変換
 	jsonData, err := json.Marshal(a.SaveData)
@@ -117,6 +221,7 @@ func (a *App) UpdateSetting(ss []Setting) {
 
 func (a *App) OpenFolderSelectWindow() string {
 	log.Default().Println("[DEBUG] [LOG] OpenFolderSelectWindow")
+	a.StopWatching()
 	// フォルダ選択ダイアログを開く
 	// 選択されたフォルダのパスを返す
 	path, err := runtime.OpenDirectoryDialog(a.ctx, runtime.OpenDialogOptions{
@@ -143,65 +248,152 @@ func (a *App) OpenFolderSelectWindow() string {
 	return path
 }
 
-// フォルダ内の最新のtxtファイルを探索し、そのファイル名を返す
+// フォルダ内(WatchRecursiveが有効な場合はサブフォルダも含む)の
+// FilePatternに一致する最新のファイルを探索し、LogPathからの相対ファイル名を返す
 func (a *App) GetNewestFileName(path string) string {
 	log.Default().Println("[DEBUG] [LOG] GetNewestFileName")
-	entries, err := os.ReadDir(path)
-	if err != nil {
-		log.Fatal(err)
-	}
-	var newestFile os.DirEntry
+	pattern := a.filePattern()
+	var newestPath string
 	var newestTime time.Time
-	for _, entry := range entries {
-		if !entry.IsDir() {
-			info, err := entry.Info()
+
+	visit := func(fullPath string, d os.DirEntry) {
+		if d.IsDir() {
+			return
+		}
+		if matched, err := filepath.Match(pattern, d.Name()); err != nil || !matched {
+			log.Default().Println("[DEBUG] [LOG] does not match pattern: " + d.Name())
+			return
+		}
+		info, err := d.Info()
+		if err != nil {
+			runtime.EventsEmit(a.ctx, "commonLogOutput", "ERRPR:"+err.Error())
+			return
+		}
+		if info.Size() == 0 {
+			log.Default().Println("[DEBUG] [LOG] is empty: " + d.Name())
+			return
+		}
+		if info.ModTime().After(newestTime) {
+			newestPath = fullPath
+			newestTime = info.ModTime()
+		}
+	}
+
+	if a.SaveData.WatchRecursive {
+		err := filepath.WalkDir(path, func(p string, d os.DirEntry, err error) error {
 			if err != nil {
-				runtime.EventsEmit(a.ctx, "commonLogOutput", "ERRPR:"+err.Error())
-			}
-			// 拡張子が.txtのファイルのみを対象とする
-			if filepath.Ext(entry.Name()) != ".txt" {
-				log.Default().Println("[DEBUG] [LOG] is not text: " + entry.Name())
-				continue
-			}
-			if info.IsDir() || info.Size() == 0 {
-				log.Default().Println("[DEBUG] [LOG] is Directory or empty: " + entry.Name())
-				continue
-			}
-			if info.ModTime().After(newestTime) {
-				// log.Default().Println("[DEBUG] [LOG] 最新のファイルに更新があります=> " + entry.Name() + info.ModTime().String())
-				newestFile = entry
-				newestTime = info.ModTime()
+				return nil
 			}
+			visit(p, d)
+			return nil
+		})
+		if err != nil {
+			runtime.EventsEmit(a.ctx, "commonLogOutput", "ERRPR:"+err.Error())
+		}
+	} else {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			runtime.EventsEmit(a.ctx, "commonLogOutput", "ERRPR:"+err.Error())
+			return ""
+		}
+		for _, entry := range entries {
+			visit(filepath.Join(path, entry.Name()), entry)
 		}
 	}
-	if newestFile != nil {
-		a.targetFileName = newestFile.Name()
-		return newestFile.Name()
+
+	if newestPath == "" {
+		return ""
+	}
+	relPath, err := filepath.Rel(path, newestPath)
+	if err != nil {
+		relPath = filepath.Base(newestPath)
 	}
-	return ""
+	// VRChat (and therefore this app) only targets Windows, so targetFileName
+	// is always normalized to "\"-separated form here to match the literal
+	// LogPath+"\\"+targetFileName concatenation used everywhere else
+	// (WatchFile's fsnotify event comparison, SetFileName, ReadFile callers).
+	// filepath.Rel returns "/"-separated paths on non-Windows build/test
+	// hosts, which would otherwise break nested-file rotation detection.
+	relPath = strings.ReplaceAll(relPath, "/", "\\")
+	a.setTargetFileName(relPath)
+	return relPath
 }
 
 // fsnotifyでの ファイルの監視を開始する
 func (a *App) WatchFile() {
 	log.Default().Println("[DEBUG] [LOG] Start watching file")
-	lastOffset = 0
+	a.StopWatching()
+	a.setLastOffset(0)
+
+	ctx, cancel := context.WithCancel(a.ctx)
+	a.watchMutex.Lock()
+	a.watchCancel = cancel
+	a.watchMutex.Unlock()
+	a.watchWG.Add(1)
+
 	watcher, err := fsnotify.NewWatcher()
 	if err != nil {
 		log.Fatal(err)
 	}
-	defer watcher.Close()
 
-	done := make(chan bool)
+	watchedDirs := map[string]bool{}
+	addWatchDir := func(dir string) {
+		if watchedDirs[dir] {
+			return
+		}
+		if len(watchedDirs) >= maxWatchedDirs {
+			log.Default().Println("[DEBUG] [LOG] too many watched directories, skipping: " + dir)
+			return
+		}
+		if err := watcher.Add(dir); err != nil {
+			runtime.EventsEmit(a.ctx, "commonLogOutput", "ERRPR:"+err.Error())
+			return
+		}
+		watchedDirs[dir] = true
+	}
+
+	addWatchDir(a.SaveData.LogPath)
+	if a.SaveData.WatchRecursive {
+		err := filepath.WalkDir(a.SaveData.LogPath, func(p string, d os.DirEntry, err error) error {
+			if err != nil || !d.IsDir() {
+				return nil
+			}
+			addWatchDir(p)
+			return nil
+		})
+		if err != nil {
+			runtime.EventsEmit(a.ctx, "commonLogOutput", "ERRPR:"+err.Error())
+		}
+	}
+
 	go func() {
+		defer a.watchWG.Done()
+		defer watcher.Close()
 		for {
 			select {
+			case <-ctx.Done():
+				return
 			case event, ok := <-watcher.Events:
 				if !ok {
 					return
 				}
-				fullpath := a.SaveData.LogPath + "\\" + a.targetFileName
+				if a.SaveData.WatchRecursive && event.Op&fsnotify.Create == fsnotify.Create {
+					if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+						addWatchDir(event.Name)
+					}
+				}
+				previousFileName := a.getTargetFileName()
+				// GetNewestFileName also updates a.targetFileName as a side effect.
+				if newest := a.GetNewestFileName(a.SaveData.LogPath); newest != "" && newest != previousFileName {
+					log.Default().Println("[DEBUG] [LOG] log rotated: " + previousFileName + " -> " + newest)
+					a.setLastOffset(0)
+					runtime.EventsEmit(a.ctx, "logRotated", newest)
+				}
+				fullpath := a.SaveData.LogPath + "\\" + a.getTargetFileName()
 				if event.Name == fullpath {
-					a.ReadFile(fullpath)
+					if err := a.ReadFile(fullpath); err != nil {
+						runtime.EventsEmit(a.ctx, "commonLogOutput", "ERRPR:"+err.Error())
+					}
 				}
 			case err, ok := <-watcher.Errors:
 				if !ok {
@@ -211,46 +403,97 @@ func (a *App) WatchFile() {
 			}
 		}
 	}()
+}
 
-	err = watcher.Add(a.SaveData.LogPath)
-	if err != nil {
-		log.Fatal(err)
+// StopWatching cancels the currently running WatchFile goroutine, if any, and
+// blocks until it has actually returned. This is required so a caller that
+// immediately overwrites targetFileName/lastOffset (SetFileName,
+// OpenFolderSelectWindow) can't race the outgoing goroutine, which may still
+// be mid-iteration when cancel() is called.
+func (a *App) StopWatching() {
+	a.watchMutex.Lock()
+	cancel := a.watchCancel
+	a.watchCancel = nil
+	a.watchMutex.Unlock()
+	if cancel != nil {
+		cancel()
 	}
-	<-done
+	a.watchWG.Wait()
+}
+
+// getTargetFileName and setTargetFileName guard a.targetFileName, which is
+// read and written from both the watch goroutine and JS-invoked methods.
+func (a *App) getTargetFileName() string {
+	a.watchMutex.Lock()
+	defer a.watchMutex.Unlock()
+	return a.targetFileName
 }
 
+func (a *App) setTargetFileName(name string) {
+	a.watchMutex.Lock()
+	a.targetFileName = name
+	a.watchMutex.Unlock()
+}
+
+// lastOffset is the byte offset already read from the currently watched file.
+// It's guarded by watchMutex for the same reason as targetFileName.
 var lastOffset int64
+
+func (a *App) getLastOffset() int64 {
+	a.watchMutex.Lock()
+	defer a.watchMutex.Unlock()
+	return lastOffset
+}
+
+func (a *App) setLastOffset(v int64) {
+	a.watchMutex.Lock()
+	lastOffset = v
+	a.watchMutex.Unlock()
+}
+
 var readFileName string
 
 func (a *App) ResetOffset() {
-	lastOffset = 0
+	a.setLastOffset(0)
 }
 
-func (a *App) ReadFile(path string) {
+func (a *App) ReadFile(path string) error {
 	log.Default().Println("[DEBUG] [LOG] call readFile")
-	log.Default().Println("[DEBUG] [LOG] lastOffset: ", lastOffset)
+	offset := a.getLastOffset()
+	log.Default().Println("[DEBUG] [LOG] lastOffset: ", offset)
+	// ファイルがクリアされた場合などtruncateを検知したらoffsetを巻き戻す
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if info.Size() < offset {
+		log.Default().Println("[DEBUG] [LOG] file truncated, resetting offset")
+		offset = 0
+	}
 	file, err := os.Open(path)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 	defer file.Close()
 	// Seek to the last offset
-	_, err = file.Seek(lastOffset, 0)
+	_, err = file.Seek(offset, 0)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
 		a.evaluateLine(scanner.Text())
 	}
 	if err := scanner.Err(); err != nil {
-		log.Fatal(err)
+		return err
 	}
-	lastOffset, err = file.Seek(0, io.SeekCurrent)
+	offset, err = file.Seek(0, io.SeekCurrent)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
-	log.Default().Println("[DEBUG] [LOG] newOffset: ", lastOffset)
+	a.setLastOffset(offset)
+	log.Default().Println("[DEBUG] [LOG] newOffset: ", offset)
+	return nil
 }
 
 // 行の評価
@@ -262,13 +505,15 @@ func (a *App) evaluateLine(line string) {
 			matches := pattern.FindString(line)
 			if matches != "" {
 				a.OutputLog(setting.Title + " : " + matches)
+				a.broadcastEvent(setting, line, matches)
 				// setting.Type によって処理を分岐
 				if setting.Type == "Web Request" {
 					runtime.EventsEmit(a.ctx, "commonLogOutput", "Web Request:"+matches)
-					message := a.HttpPost(matches, setting.Title, setting.URL)
+					message := a.HttpPost(line, matches, setting, pattern)
 					runtime.EventsEmit(a.ctx, "commonLogOutput", message)
 				} else if setting.Type == "xs" {
-					// a.XSOverray(matches)
+					message := a.XSOverlay(matches, setting)
+					runtime.EventsEmit(a.ctx, "commonLogOutput", message)
 				} else if setting.Type == "log" {
 					// a.LogOutput(matches)
 				}
@@ -318,7 +563,8 @@ func (a *App) evaluateLine(line string) {
 // 	return "OK"
 // }
 
-func (a *App) HttpPost(eventString string, title string, url string) string {
+func (a *App) HttpPost(line string, eventString string, setting Setting, pattern *regexp.Regexp) string {
+	url := setting.URL
 	if url == "" {
 		return "URL is empty"
 	}
@@ -326,19 +572,358 @@ func (a *App) HttpPost(eventString string, title string, url string) string {
 	if !strings.HasPrefix(url, "http") {
 		return "URL is invalid"
 	}
-	data := new(HttpRequestModel)
-	data.Value = eventString
-	data.Title = title
-	data_json, _ := json.Marshal(data)
-	res, err := http.Post(url, "application/json", bytes.NewBuffer(data_json))
+
+	method := setting.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+	contentType := setting.ContentType
+	if contentType == "" {
+		contentType = "application/json"
+	}
+
+	var bodyBytes []byte
+	if setting.BodyTemplate != "" {
+		rendered, err := a.renderBodyTemplate(setting, pattern, line, eventString, contentType)
+		if err != nil {
+			return "ERROR:" + err.Error()
+		}
+		bodyBytes = rendered
+	} else {
+		data := new(HttpRequestModel)
+		data.Value = eventString
+		data.Title = setting.Title
+		bodyBytes, _ = json.Marshal(data)
+	}
+
+	req, err := http.NewRequest(method, url, bytes.NewBuffer(bodyBytes))
 	if err != nil {
-		log.Fatal(err)
+		return "ERROR:" + err.Error()
+	}
+	req.Header.Set("Content-Type", contentType)
+	for key, value := range setting.Headers {
+		req.Header.Set(key, value)
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "ERROR:" + err.Error()
 	}
 	defer res.Body.Close()
 	body, err := io.ReadAll(res.Body)
 	if err != nil {
-		log.Fatal(err)
+		return "ERROR:" + err.Error()
+	}
+	// webhook endpoints (Discord/Slack/Pushover) return 4xx on a malformed
+	// body; without this the frontend showed "OK" while notifications
+	// silently stopped working.
+	if res.StatusCode >= 400 {
+		return fmt.Sprintf("ERROR:%d %s", res.StatusCode, string(body))
+	}
+	log.Default().Println(string(body))
+	return "OK"
+}
+
+// bodyTemplateFuncs returns the funcs available to a Setting's BodyTemplate.
+// BodyTemplate renders into a JSON (or JSON-like) request body, so fields
+// such as {{.Groups.user}} or {{.Match}} must go through json (e.g.
+// {{.Groups.user | json}}) to get
+// properly quoted/escaped instead of splicing raw, possibly-untrusted text
+// (a VRChat display name can contain `"`, `\`, or a newline) into the body.
+func bodyTemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"json": func(v string) (string, error) {
+			b, err := json.Marshal(v)
+			if err != nil {
+				return "", err
+			}
+			return string(b), nil
+		},
+	}
+}
+
+// isJSONContentType reports whether contentType (the header HttpPost will send,
+// defaulting to application/json like HttpPost itself) is a JSON media type.
+func isJSONContentType(contentType string) bool {
+	ct := strings.ToLower(strings.TrimSpace(contentType))
+	return ct == "" || strings.HasPrefix(ct, "application/json")
+}
+
+// renderBodyTemplate renders setting.BodyTemplate as a text/template, exposing the
+// matched line, title, timestamp and any named regexp capture groups to it.
+func (a *App) renderBodyTemplate(setting Setting, pattern *regexp.Regexp, line string, eventString string, contentType string) ([]byte, error) {
+	groups := map[string]string{}
+	if sub := pattern.FindStringSubmatch(line); sub != nil {
+		for i, name := range pattern.SubexpNames() {
+			if i == 0 || name == "" {
+				continue
+			}
+			groups[name] = sub[i]
+		}
+	}
+	data := HttpBodyTemplateData{
+		Match:     eventString,
+		Line:      line,
+		Title:     setting.Title,
+		Timestamp: time.Now().Format(time.RFC3339),
+		Groups:    groups,
+	}
+	tmpl, err := template.New(setting.ID).Funcs(bodyTemplateFuncs()).Parse(setting.BodyTemplate)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	if isJSONContentType(contentType) && !json.Valid(buf.Bytes()) {
+		return nil, fmt.Errorf("bodyTemplate did not render to valid JSON for ContentType %q; pipe untrusted fields such as .Match/.Line/.Groups.x through \"| json\" to escape them", contentType)
+	}
+	return buf.Bytes(), nil
+}
+
+// xsOverlayAPIURL is XSOverlay's local authenticated HTTP command endpoint.
+const xsOverlayAPIURL = "http://localhost:42070/api/v1/command/"
+
+// xsOverlayWebSocketURL is XSOverlay's local unauthenticated WebSocket endpoint,
+// used when no XSOverlayAPIKey is configured.
+const xsOverlayWebSocketURL = "ws://localhost:42070/?client_name=vrcwd"
+
+// XSOverlay sends a SendNotification command to XSOverlay for a matched line,
+// over the authenticated HTTP API if an XSOverlayAPIKey is configured, or over
+// the unauthenticated WebSocket API otherwise.
+func (a *App) XSOverlay(eventString string, setting Setting) string {
+	timeout := setting.Timeout
+	if timeout == 0 {
+		timeout = 5
+	}
+	opacity := setting.Opacity
+	if opacity == 0 {
+		opacity = 1
+	}
+	payload := XSOverrayModel{
+		Type:      1,
+		Timeout:   timeout,
+		Title:     setting.Title,
+		Content:   eventString,
+		Icon:      setting.Icon,
+		Opacity:   opacity,
+		SourceApp: "vrcwd",
+	}
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		return "ERROR:" + err.Error()
+	}
+	message := XSOverlayMessageModel{
+		Sender:   "vrcwd",
+		Target:   "xsoverlay",
+		Command:  "SendNotification",
+		JsonData: string(payloadJSON),
+	}
+	messageJSON, err := json.Marshal(message)
+	if err != nil {
+		return "ERROR:" + err.Error()
+	}
+
+	if a.SaveData.XSOverlayAPIKey == "" {
+		return a.sendXSOverlayWebSocket(messageJSON)
+	}
+	return a.sendXSOverlayHTTP(messageJSON)
+}
+
+func (a *App) sendXSOverlayHTTP(messageJSON []byte) string {
+	req, err := http.NewRequest(http.MethodPost, xsOverlayAPIURL, bytes.NewBuffer(messageJSON))
+	if err != nil {
+		return "ERROR:" + err.Error()
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+a.SaveData.XSOverlayAPIKey)
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "ERROR:" + err.Error()
+	}
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return "ERROR:" + err.Error()
 	}
 	log.Default().Println(string(body))
 	return "OK"
 }
+
+// sendXSOverlayWebSocket sends a single command over XSOverlay's unauthenticated
+// WebSocket API and closes the connection.
+func (a *App) sendXSOverlayWebSocket(messageJSON []byte) string {
+	conn, _, err := websocket.DefaultDialer.Dial(xsOverlayWebSocketURL, nil)
+	if err != nil {
+		return "ERROR:" + err.Error()
+	}
+	defer conn.Close()
+	if err := conn.WriteMessage(websocket.TextMessage, messageJSON); err != nil {
+		return "ERROR:" + err.Error()
+	}
+	return "OK"
+}
+
+// defaultBroadcastPort is used when SaveData.BroadcastPort is unset.
+const defaultBroadcastPort = 17429
+
+// broadcastClientBufferSize is how many queued frames a slow client can fall
+// behind by before broadcastEvent starts dropping its oldest frame.
+const broadcastClientBufferSize = 16
+
+var broadcastUpgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			return true
+		}
+		u, err := url.Parse(origin)
+		if err != nil {
+			return false
+		}
+		host := u.Hostname()
+		return host == "localhost" || host == "127.0.0.1" || host == "::1"
+	},
+}
+
+// StartBroadcastServer starts the localhost-only WebSocket server that fans
+// out match events to external tools (OBS overlays, OSC bridges, etc).
+func (a *App) StartBroadcastServer() {
+	a.broadcastMutex.Lock()
+	defer a.broadcastMutex.Unlock()
+	if a.broadcastServer != nil {
+		log.Default().Println("[DEBUG] [LOG] broadcast server already running")
+		return
+	}
+
+	port := a.SaveData.BroadcastPort
+	if port == 0 {
+		port = defaultBroadcastPort
+	}
+
+	a.broadcastClients = map[*broadcastClient]bool{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", a.handleBroadcastConn)
+	server := &http.Server{
+		Addr:    fmt.Sprintf("127.0.0.1:%d", port),
+		Handler: mux,
+	}
+	a.broadcastServer = server
+
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			runtime.EventsEmit(a.ctx, "commonLogOutput", "ERRPR:"+err.Error())
+		}
+	}()
+}
+
+// StopBroadcastServer shuts down the broadcast server and disconnects any
+// subscribed clients.
+func (a *App) StopBroadcastServer() {
+	a.broadcastMutex.Lock()
+	server := a.broadcastServer
+	clients := a.broadcastClients
+	a.broadcastServer = nil
+	a.broadcastClients = nil
+	a.broadcastMutex.Unlock()
+
+	if server == nil {
+		return
+	}
+	for client := range clients {
+		client.conn.Close()
+	}
+	if err := server.Close(); err != nil {
+		runtime.EventsEmit(a.ctx, "commonLogOutput", "ERRPR:"+err.Error())
+	}
+}
+
+func (a *App) handleBroadcastConn(w http.ResponseWriter, r *http.Request) {
+	conn, err := broadcastUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		runtime.EventsEmit(a.ctx, "commonLogOutput", "ERRPR:"+err.Error())
+		return
+	}
+	client := &broadcastClient{conn: conn, send: make(chan []byte, broadcastClientBufferSize)}
+
+	a.broadcastMutex.Lock()
+	if a.broadcastClients == nil {
+		a.broadcastMutex.Unlock()
+		conn.Close()
+		return
+	}
+	a.broadcastClients[client] = true
+	count := len(a.broadcastClients)
+	a.broadcastMutex.Unlock()
+	runtime.EventsEmit(a.ctx, "broadcastClientCount", count)
+
+	go func() {
+		defer a.removeBroadcastClient(client)
+		for payload := range client.send {
+			if err := conn.WriteMessage(websocket.TextMessage, payload); err != nil {
+				return
+			}
+		}
+	}()
+
+	// 外部ツールからのメッセージは使用しないが、切断を検知するために読み捨てる
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			a.removeBroadcastClient(client)
+			return
+		}
+	}
+}
+
+// removeBroadcastClient deletes client from the subscriber map before closing
+// its channel/connection, so broadcastEvent (which only sends under the same
+// lock while the client is still in the map) can never race a send against
+// close. The reader and writer goroutines both defer to this, so once guards
+// against running the teardown twice.
+func (a *App) removeBroadcastClient(client *broadcastClient) {
+	client.once.Do(func() {
+		a.broadcastMutex.Lock()
+		delete(a.broadcastClients, client)
+		count := len(a.broadcastClients)
+		a.broadcastMutex.Unlock()
+		close(client.send)
+		client.conn.Close()
+		runtime.EventsEmit(a.ctx, "broadcastClientCount", count)
+	})
+}
+
+// broadcastEvent fans a matched line out to every subscribed WebSocket client.
+// A slow client has its oldest queued frame dropped instead of blocking log parsing.
+func (a *App) broadcastEvent(setting Setting, line string, matched string) {
+	a.broadcastMutex.Lock()
+	defer a.broadcastMutex.Unlock()
+	if len(a.broadcastClients) == 0 {
+		return
+	}
+	event := BroadcastEvent{
+		SettingID: setting.ID,
+		Title:     setting.Title,
+		Type:      setting.Type,
+		Match:     matched,
+		Line:      line,
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	for client := range a.broadcastClients {
+		select {
+		case client.send <- payload:
+		default:
+			select {
+			case <-client.send:
+			default:
+			}
+			select {
+			case client.send <- payload:
+			default:
+			}
+		}
+	}
+}
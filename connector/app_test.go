@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	goruntime "runtime"
+	"testing"
+	"time"
+)
+
+// TestWatchFileStartStopRestart starts, stops, and restarts the watcher
+// repeatedly and checks that no goroutines are leaked, per the chunk0-2
+// requirement that concurrent Start/Stop calls from the JS side don't leak
+// goroutines or panic on double-close.
+func TestWatchFileStartStopRestart(t *testing.T) {
+	app := &App{ctx: context.Background()}
+	app.SaveData.LogPath = t.TempDir()
+
+	goruntime.GC()
+	before := goruntime.NumGoroutine()
+
+	for i := 0; i < 100; i++ {
+		app.WatchFile()
+		app.StopWatching()
+	}
+
+	goruntime.GC()
+	after := goruntime.NumGoroutine()
+	// StopWatching waits for the watch goroutine itself to return, but its
+	// deferred watcher.Close() schedules OS-level teardown asynchronously,
+	// so allow a brief, bounded settle instead of asserting instantly.
+	deadline := time.Now().Add(2 * time.Second)
+	for after > before && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+		goruntime.GC()
+		after = goruntime.NumGoroutine()
+	}
+
+	if after > before {
+		t.Fatalf("goroutine leak after 100x start/stop: before=%d after=%d", before, after)
+	}
+}
+
+// TestWatchFileHandlesRotationAndTruncation covers the chunk0-4 rotation
+// handling by renaming/rewriting files under a temp dir, simulating VRChat
+// rolling to a new output_log_*.txt file and the user clearing a log.
+func TestWatchFileHandlesRotationAndTruncation(t *testing.T) {
+	cases := []struct {
+		name string
+		run  func(t *testing.T, dir string, app *App)
+	}{
+		{
+			name: "switches to a newer rotated file",
+			run: func(t *testing.T, dir string, app *App) {
+				oldPath := filepath.Join(dir, "output_log_2026-01-01.txt")
+				writeTestFile(t, oldPath, "line one\nline two\n")
+				time.Sleep(10 * time.Millisecond)
+
+				if got := app.GetNewestFileName(dir); got != "output_log_2026-01-01.txt" {
+					t.Fatalf("got %q, want output_log_2026-01-01.txt", got)
+				}
+				if err := app.ReadFile(filepath.Join(dir, app.getTargetFileName())); err != nil {
+					t.Fatalf("ReadFile: %v", err)
+				}
+
+				// VRChat rolls to a new file at midnight; archive the old one
+				// (renaming it out of FilePattern's match) and drop in a newer file.
+				if err := os.Rename(oldPath, filepath.Join(dir, "archived_output_log_2026-01-01.txt")); err != nil {
+					t.Fatalf("rename: %v", err)
+				}
+				writeTestFile(t, filepath.Join(dir, "output_log_2026-01-02.txt"), "line three\n")
+
+				if got := app.GetNewestFileName(dir); got != "output_log_2026-01-02.txt" {
+					t.Fatalf("got %q, want output_log_2026-01-02.txt after rotation", got)
+				}
+			},
+		},
+		{
+			name: "resets offset when the file is truncated",
+			run: func(t *testing.T, dir string, app *App) {
+				path := filepath.Join(dir, "output_log_2026-01-01.txt")
+				writeTestFile(t, path, "line one\nline two\nline three\n")
+
+				if err := app.ReadFile(path); err != nil {
+					t.Fatalf("ReadFile: %v", err)
+				}
+				if app.getLastOffset() == 0 {
+					t.Fatalf("expected a non-zero offset after the first read")
+				}
+
+				// simulate the user clearing the log file mid-session
+				short := "short\n"
+				writeTestFile(t, path, short)
+				if err := app.ReadFile(path); err != nil {
+					t.Fatalf("ReadFile after truncation: %v", err)
+				}
+				if got := app.getLastOffset(); got != int64(len(short)) {
+					t.Fatalf("offset after truncation = %d, want %d", got, len(short))
+				}
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			app := &App{ctx: context.Background()}
+			dir := t.TempDir()
+			app.SaveData.LogPath = dir
+			tc.run(t, dir, app)
+		})
+	}
+}
+
+func writeTestFile(t *testing.T, path string, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("WriteFile(%s): %v", path, err)
+	}
+}